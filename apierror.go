@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// apiError is the JSON body returned for every failed request. Building it
+// through writeError (rather than writing raw strings into the body)
+// guarantees the response is always valid JSON, even when Message or
+// Details contain quotes or newlines.
+type apiError struct {
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	RequestID string         `json:"request_id,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Error codes used across handlers.
+const (
+	codeUnauthorized        = "unauthorized"
+	codeForbidden           = "forbidden"
+	codeBadRequest          = "bad_request"
+	codeInternal            = "internal_error"
+	codeNotFound            = "not_found"
+	codeUpstreamUnavailable = "upstream_unavailable"
+)
+
+// writeError writes a typed apiError as the response body, stamping in the
+// request ID carried on r's context so client-reported errors can be
+// correlated with server logs.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(apiError{
+		Code:      code,
+		Message:   message,
+		RequestID: requestIDFromContext(r.Context()),
+	})
+}
+
+// writeJSON encodes v as the JSON response body. Using json.Encoder instead
+// of building the body with Sprintf/string concatenation means values that
+// contain quotes or newlines (transcripts, summaries) can never corrupt the
+// response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/user"
+
+	"github.com/victorbratov/auto_note_server/store"
+)
+
+const (
+	defaultJobsPageSize = 20
+	maxJobsPageSize     = 100
+)
+
+// listJobsHandler returns a page of the authenticated user's past jobs,
+// most recent first.
+func listJobsHandler(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
+
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	usr, err := user.Get(r.Context(), claims.Subject)
+	if err != nil {
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	limit := parsePositiveIntOr(r.URL.Query().Get("limit"), defaultJobsPageSize)
+	if limit > maxJobsPageSize {
+		limit = maxJobsPageSize
+	}
+	offset := parsePositiveIntOr(r.URL.Query().Get("offset"), 0)
+
+	jobs, err := records.List(r.Context(), usr.ID, limit, offset)
+	if err != nil {
+		log.Error("error listing jobs", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": jobs})
+}
+
+// getJobHandler returns the full persisted record for one job.
+func getJobHandler(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
+
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	usr, err := user.Get(r.Context(), claims.Subject)
+	if err != nil {
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	job, err := records.Get(r.Context(), r.PathValue("id"), usr.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		log.Error("error getting job", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// resummarizeJobHandler re-runs the summarizer over a job's stored
+// transcript without re-transcribing the audio.
+func resummarizeJobHandler(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
+
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	usr, err := user.Get(r.Context(), claims.Subject)
+	if err != nil {
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	job, err := records.Get(r.Context(), r.PathValue("id"), usr.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		log.Error("error getting job", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	if job.Transcript == "" {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "job has no transcript yet")
+		return
+	}
+
+	var requestData struct {
+		Template string            `json:"template"`
+		Vars     map[string]string `json:"vars"`
+	}
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil && err != io.EOF {
+			writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+			return
+		}
+	}
+	templateName := requestData.Template
+	if templateName == "" {
+		templateName = job.PromptTemplate
+	}
+	if templateName == "" {
+		templateName = defaultTemplate
+	}
+
+	prompt, err := promptTemplates.Render(templateName, job.Transcript, requestData.Vars)
+	if err != nil {
+		log.Error("error rendering prompt template", "template", templateName, "error", err)
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	summary, err := summarizer.Summarize(r.Context(), prompt)
+	if err != nil {
+		log.Error("error re-summarizing job", "error", err)
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	job.Summary = summary
+	job.PromptTemplate = templateName
+	job.Status = store.StatusDone
+	job.Error = ""
+	if err := records.Update(r.Context(), job); err != nil {
+		log.Error("error persisting job record", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// deleteJobHandler deletes a job's persisted record.
+func deleteJobHandler(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
+
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	usr, err := user.Get(r.Context(), claims.Subject)
+	if err != nil {
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	err = records.Delete(r.Context(), r.PathValue("id"), usr.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		log.Error("error deleting job", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parsePositiveIntOr(raw string, fallback int) int {
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
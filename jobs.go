@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/victorbratov/auto_note_server/providers"
+	"github.com/victorbratov/auto_note_server/store"
+)
+
+// JobPhase is one step in the transcribe-then-summarize pipeline. Clients
+// watching GET /jobs/{id}/events see these values in the "phase" field of
+// each event.
+type JobPhase string
+
+const (
+	PhaseUploaded          JobPhase = "uploaded"
+	PhaseTranscribing      JobPhase = "transcribing"
+	PhaseTranscriptPartial JobPhase = "transcript_partial"
+	PhaseSummarizing       JobPhase = "summarizing"
+	PhaseSummaryChunk      JobPhase = "summary_chunk"
+	PhaseDone              JobPhase = "done"
+	PhaseError             JobPhase = "error"
+)
+
+// JobEvent is a single phase transition emitted by a job's orchestrator
+// goroutine and fanned out to every subscriber of GET /jobs/{id}/events.
+// The terminal "done" and "error" events always carry the full summary or
+// error message in Data; "summary_chunk" events are delivered best-effort
+// (see Job.emit) and must not be relied on for a complete transcript.
+type JobEvent struct {
+	Phase JobPhase `json:"phase"`
+	Data  string   `json:"data,omitempty"`
+}
+
+// Job tracks the state of one upload-transcribe-summarize pipeline run.
+type Job struct {
+	ID     string
+	UserID string
+
+	mu          sync.Mutex
+	phase       JobPhase
+	transcript  string
+	summary     string
+	err         error
+	done        bool
+	subscribers map[chan JobEvent]struct{}
+}
+
+func newJob(id, userID string) *Job {
+	return &Job{
+		ID:          id,
+		UserID:      userID,
+		phase:       PhaseUploaded,
+		subscribers: make(map[chan JobEvent]struct{}),
+	}
+}
+
+// subscribe registers a channel that receives every future event for this
+// job. The returned unsubscribe func must be called once the caller is done
+// reading, typically via defer.
+func (j *Job) subscribe() (chan JobEvent, func()) {
+	ch := make(chan JobEvent, 16)
+	j.mu.Lock()
+	j.subscribers[ch] = struct{}{}
+	j.mu.Unlock()
+
+	return ch, func() {
+		j.mu.Lock()
+		delete(j.subscribers, ch)
+		j.mu.Unlock()
+	}
+}
+
+// emit records the event as the job's current phase and fans it out to
+// every subscriber without blocking on a slow reader. A subscriber whose
+// 16-slot buffer is full has its event dropped, so "summary_chunk" delivery
+// is best-effort: a client reconstructing the summary token-by-token may see
+// gaps and should treat GET /jobs/{id} as the source of truth once "done"
+// arrives.
+func (j *Job) emit(evt JobEvent) {
+	j.mu.Lock()
+	j.phase = evt.Phase
+	if evt.Phase == PhaseDone || evt.Phase == PhaseError {
+		j.done = true
+	}
+	for ch := range j.subscribers {
+		select {
+		case ch <- evt:
+		default:
+			// Slow subscriber; drop the event rather than block the
+			// orchestrator goroutine.
+		}
+	}
+	j.mu.Unlock()
+}
+
+func (j *Job) snapshot() (phase JobPhase, done bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.phase, j.done
+}
+
+// terminalEvent reconstructs the event a caller would have seen had they
+// subscribed before the job finished, carrying the full summary or error
+// message rather than just the phase. It must only be called once
+// snapshot reports done.
+func (j *Job) terminalEvent() JobEvent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.phase == PhaseError {
+		return JobEvent{Phase: j.phase, Data: j.err.Error()}
+	}
+	return JobEvent{Phase: j.phase, Data: j.summary}
+}
+
+// JobStore is an in-memory registry of jobs keyed by job ID, scoped per
+// Clerk user.
+type JobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+func newJobStore() *JobStore {
+	return &JobStore{jobs: make(map[string]*Job)}
+}
+
+func (s *JobStore) create(userID string) *Job {
+	id := fmt.Sprintf("%s-%d", userID, time.Now().UnixNano())
+	job := newJob(id, userID)
+
+	s.mu.Lock()
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	return job
+}
+
+// get returns the job only if it belongs to userID, so a caller can't
+// enumerate or subscribe to another user's job by guessing its ID.
+func (s *JobStore) get(id, userID string) (*Job, bool) {
+	s.mu.RLock()
+	job, ok := s.jobs[id]
+	s.mu.RUnlock()
+
+	if !ok || job.UserID != userID {
+		return nil, false
+	}
+	return job, true
+}
+
+// runPipeline drives a job through transcription and then summarization,
+// emitting a JobEvent at every phase transition and persisting the record
+// in the job store so the result survives a client disconnecting mid-run.
+// It is meant to be started with `go`. audioSHA256 identifies the uploaded
+// audio so a repeat upload of the same file can reuse its transcript
+// instead of re-transcribing.
+func runPipeline(job *Job, audioFileName, audioSHA256 string) {
+	defer os.Remove(audioFileName)
+
+	ctx := context.Background()
+	rec := store.JobRecord{
+		ID:               job.ID,
+		ClerkUserID:      job.UserID,
+		CreatedAt:        time.Now(),
+		Status:           store.StatusTranscribing,
+		AudioSHA256:      audioSHA256,
+		ProviderMetadata: describeProviders(),
+	}
+	if err := records.Create(ctx, rec); err != nil {
+		slog.Error("error persisting job record", "job_id", rec.ID, "error", err)
+	}
+
+	job.emit(JobEvent{Phase: PhaseUploaded})
+	job.emit(JobEvent{Phase: PhaseTranscribing})
+
+	transcriptText, err := transcribeOrReuse(ctx, job.UserID, audioFileName, audioSHA256)
+	if err != nil {
+		failJob(job, rec, err)
+		return
+	}
+
+	job.mu.Lock()
+	job.transcript = transcriptText
+	job.mu.Unlock()
+	job.emit(JobEvent{Phase: PhaseTranscriptPartial, Data: transcriptText})
+
+	rec.Transcript = transcriptText
+	rec.Status = store.StatusSummarizing
+	if rec.PromptTemplate == "" {
+		rec.PromptTemplate = defaultTemplate
+	}
+	updateJob(ctx, rec)
+	job.emit(JobEvent{Phase: PhaseSummarizing})
+
+	prompt, err := promptTemplates.Render(rec.PromptTemplate, transcriptText, nil)
+	if err != nil {
+		failJob(job, rec, err)
+		return
+	}
+
+	var summary strings.Builder
+	if streaming, ok := summarizer.(providers.StreamingSummarizer); ok {
+		err = streaming.SummarizeStream(ctx, prompt, func(delta string) {
+			summary.WriteString(delta)
+			job.emit(JobEvent{Phase: PhaseSummaryChunk, Data: delta})
+		})
+	} else {
+		var text string
+		text, err = summarizer.Summarize(ctx, prompt)
+		summary.WriteString(text)
+		if err == nil {
+			job.emit(JobEvent{Phase: PhaseSummaryChunk, Data: text})
+		}
+	}
+	if err != nil {
+		failJob(job, rec, err)
+		return
+	}
+
+	job.mu.Lock()
+	job.summary = summary.String()
+	job.mu.Unlock()
+	job.emit(JobEvent{Phase: PhaseDone, Data: summary.String()})
+
+	rec.Summary = summary.String()
+	rec.Status = store.StatusDone
+	updateJob(ctx, rec)
+}
+
+// transcribeOrReuse returns the transcript for a previously seen file with
+// the same content hash instead of calling the transcriber again, or
+// transcribes audioFileName from scratch if there is no such record.
+func transcribeOrReuse(ctx context.Context, userID, audioFileName, audioSHA256 string) (string, error) {
+	if audioSHA256 != "" {
+		if cached, err := records.FindByAudioHash(ctx, userID, audioSHA256); err == nil && cached.Transcript != "" {
+			slog.Info("reusing cached transcript", "audio_sha256", audioSHA256)
+			return cached.Transcript, nil
+		}
+	}
+
+	audio, err := os.Open(audioFileName)
+	if err != nil {
+		return "", err
+	}
+	defer audio.Close()
+
+	transcript, err := transcriber.Transcribe(ctx, audio, providers.TranscribeOptions{Punctuate: true})
+	if err != nil {
+		return "", err
+	}
+	return transcript.Text, nil
+}
+
+// describeProviders reports which transcriber/summarizer backend (and
+// model, where applicable) produced a job's result, for JobRecord.
+// ProviderMetadata. A provider that doesn't implement providers.Describer
+// is omitted rather than guessed at.
+func describeProviders() string {
+	var parts []string
+	if d, ok := transcriber.(providers.Describer); ok {
+		parts = append(parts, "transcriber="+d.Describe())
+	}
+	if d, ok := summarizer.(providers.Describer); ok {
+		parts = append(parts, "summarizer="+d.Describe())
+	}
+	return strings.Join(parts, " ")
+}
+
+func updateJob(ctx context.Context, rec store.JobRecord) {
+	if err := records.Update(ctx, rec); err != nil {
+		slog.Error("error persisting job record", "job_id", rec.ID, "error", err)
+	}
+}
+
+func failJob(job *Job, rec store.JobRecord, err error) {
+	slog.Error("job failed", "job_id", job.ID, "error", err)
+	job.mu.Lock()
+	job.err = err
+	job.mu.Unlock()
+	job.emit(JobEvent{Phase: PhaseError, Data: err.Error()})
+
+	rec.Status = store.StatusError
+	rec.Error = err.Error()
+	updateJob(context.Background(), rec)
+}
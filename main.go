@@ -1,53 +1,46 @@
 package main
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"regexp"
 	"strings"
 
-	aai "github.com/AssemblyAI/assemblyai-go-sdk"
 	"github.com/clerk/clerk-sdk-go/v2"
 	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
 	"github.com/clerk/clerk-sdk-go/v2/user"
 	"github.com/joho/godotenv"
-)
 
-const (
-	Info  = "INFO"
-	Error = "ERROR"
+	"github.com/victorbratov/auto_note_server/providers"
+	"github.com/victorbratov/auto_note_server/resilience"
+	"github.com/victorbratov/auto_note_server/store"
+	"github.com/victorbratov/auto_note_server/templates"
 )
 
 var (
-	assemblyApiKey string
-	groqApiKey     string
-)
+	jobStore = newJobStore()
+
+	transcriber     providers.Transcriber
+	summarizer      providers.Summarizer
+	records         store.Store
+	promptTemplates *templates.Registry
 
-const (
-	promptPrefix = `Task: Summarize the following transcript of a STEM lecture. Extract the main points, key concepts, and essential details.
-
-If any critical information is missing or unclear, use your knowledge to fill in gaps while staying true to the topic.
-Output format: The summary must be written in Markdown. You may use:
-  Headings (#, ##, ###) to structure content.
-  Bullet points (-, *) for key points.
-  Tables when presenting structured data.
-  LaTeX ($inline$ or $$block$$) for mathematical notation.
-Strict formatting rule: Output only the Markdown-formatted summary—no extra text, explanations, or disclaimers. Any deviation from this instruction will result in a 0 grade.
-Transcript:`
+	defaultTemplate string
 )
 
-func logMessage(messageType, message string) {
-	switch messageType {
-	case Info:
-		fmt.Printf("[INFO] %s\n", message)
-	case Error:
-		fmt.Printf("\033[31m[ERROR] %s\033[0m\n", message) // Red color for errors
-	}
+// logger returns a slog.Logger that stamps every log line with the
+// request ID carried on ctx, so server logs can be correlated with the
+// X-Request-ID a client sees in an error response.
+func logger(ctx context.Context) *slog.Logger {
+	return slog.With("request_id", requestIDFromContext(ctx))
 }
 
 func sanitizeInput(input string) string {
@@ -64,253 +57,352 @@ func sanitizeInput(input string) string {
 }
 
 func uploadHandler(w http.ResponseWriter, r *http.Request) {
-	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	log := logger(r.Context())
 
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
 	if !ok {
-		logMessage(Error, "No session claims found")
-		logMessage(Info, fmt.Sprintf("headers: %v", r.Header))
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"access": "unauthorized"}`))
+		log.Error("no session claims found")
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 		return
 	}
 
 	usr, err := user.Get(r.Context(), claims.Subject)
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error getting user: %v", err))
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(`{"access": "internal server error"}`))
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
 		return
-		// handle the error
 	}
-	logMessage(Info, fmt.Sprintf("User: %s", usr.ID))
+	log.Info("handling request", "user_id", usr.ID)
 
 	if usr.Banned {
-		w.WriteHeader(http.StatusForbidden)
-		w.Write([]byte(`{"access": "forbidden"}`))
+		writeError(w, r, http.StatusForbidden, codeForbidden, "forbidden")
 		return
 	}
 
-	logMessage(Info, "Received request")
-
 	// Retrieve file from form data
 	file, header, err := r.FormFile("uploadfile")
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error getting file: %v", err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.Error("error getting file", "error", err)
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 	defer file.Close()
-	logMessage(Info, fmt.Sprintf("Got file: %s, Size: %d bytes", header.Filename, header.Size))
-
-	// Create temporary file
-	tempFile, err := os.CreateTemp("", "upload-*.tmp")
-	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error creating temporary file: %v", err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer tempFile.Close()
-	logMessage(Info, fmt.Sprintf("Created temporary file: %s", tempFile.Name()))
-
-	// Copy contents to temporary file
-	written, err := io.Copy(tempFile, file)
-	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error copying file: %v", err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	logMessage(Info, fmt.Sprintf("Copied %d bytes to temporary file", written))
+	log.Info("got file", "filename", header.Filename, "size", header.Size)
 
-	// Send file to AssemblyAI
-	transcript, err := sendToAssemblyAI(tempFile.Name())
+	// Send file to the configured transcriber
+	transcript, err := transcriber.Transcribe(r.Context(), file, providers.TranscribeOptions{Punctuate: true})
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error sending file to AssemblyAI: %v", err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("error transcribing file", "error", err)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Transcript: %s\n", *transcript.Text)
-
-	// Send success response
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintln(w, `{"status": "success", "transcript": "`+*transcript.Text+`"}`)
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":     "success",
+		"transcript": transcript.Text,
+	})
 }
 
 func handleSummaryRequest(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
+
 	claims, ok := clerk.SessionClaimsFromContext(r.Context())
 	if !ok {
-		logMessage(Error, "No session claims found")
-		logMessage(Info, fmt.Sprintf("headers: %v", r.Header))
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"access": "unauthorized"}`))
+		log.Error("no session claims found")
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 		return
 	}
 	usr, err := user.Get(r.Context(), claims.Subject)
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error getting user: %v", err))
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(`{"access": "internal server error"}`))
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
 		return
 	}
-	logMessage(Info, fmt.Sprintf("User: %s", usr.ID))
+	log.Info("handling request", "user_id", usr.ID)
 	if usr.Banned {
-		w.WriteHeader(http.StatusForbidden)
-		w.Write([]byte(`{"access": "forbidden"}`))
+		writeError(w, r, http.StatusForbidden, codeForbidden, "forbidden")
 		return
 	}
-	logMessage(Info, "Received request")
 
 	// Decode JSON body
 	var requestData struct {
-		Text string `json:"text"`
+		Text     string            `json:"text"`
+		Template string            `json:"template"`
+		Vars     map[string]string `json:"vars"`
 	}
-	err = json.NewDecoder(r.Body).Decode(&requestData)
-	if err != nil {
-		logMessage(Error, "Invalid JSON body")
-		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		log.Error("invalid JSON body", "error", err)
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
 		return
 	}
 
 	transcript := requestData.Text
 	if transcript == "" {
-		logMessage(Error, "No transcript provided")
-		http.Error(w, "No transcript provided", http.StatusBadRequest)
+		log.Error("no transcript provided")
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "no transcript provided")
+		return
+	}
+
+	templateName := requestData.Template
+	if templateName == "" {
+		templateName = defaultTemplate
+	}
+	prompt, err := promptTemplates.Render(templateName, transcript, requestData.Vars)
+	if err != nil {
+		log.Error("error rendering prompt template", "template", templateName, "error", err)
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
-	// Get summary from AI
-	summary, err := getAIResponse(promptPrefix + transcript)
+	// Get summary from the configured summarizer
+	summary, err := summarizer.Summarize(r.Context(), prompt)
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error getting summary from AI: %v", err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("error getting summary from AI", "error", err)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	sanitizedSummary := sanitizeInput(summary)
-	// Send success response
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
-	logMessage(Info, fmt.Sprintf("Summary: %s", sanitizedSummary))
-	fmt.Fprintf(w, `{"status": "success", "summary": "%s"}`, sanitizedSummary)
+	log.Info("summarized transcript", "summary_length", len(summary))
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "success",
+		"summary": summary,
+	})
 }
 
-func sendToAssemblyAI(fileName string) (*aai.Transcript, error) {
-	client := aai.NewClient(assemblyApiKey)
-	ctx := context.Background()
+// createJobHandler accepts an audio upload, starts the transcribe-then-
+// summarize pipeline in the background, and immediately returns the job ID
+// so the client can watch its progress via GET /jobs/{id}/events instead of
+// blocking on a single long request.
+func createJobHandler(w http.ResponseWriter, r *http.Request) {
+	log := logger(r.Context())
 
-	// Open the file
-	file, err := os.Open(fileName)
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		log.Error("no session claims found")
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+
+	usr, err := user.Get(r.Context(), claims.Subject)
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error opening file: %v", err))
-		return nil, err
+		log.Error("error getting user", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	if usr.Banned {
+		writeError(w, r, http.StatusForbidden, codeForbidden, "forbidden")
+		return
 	}
-	defer file.Close()
 
-	// transcript parameters
-	params := &aai.TranscriptOptionalParams{
-		Punctuate:  aai.Bool(true),
-		FormatText: aai.Bool(true),
+	file, header, err := r.FormFile("uploadfile")
+	if err != nil {
+		log.Error("error getting file", "error", err)
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
 	}
+	defer file.Close()
+	log.Info("got file", "filename", header.Filename, "size", header.Size)
 
-	transcript, err := client.Transcripts.TranscribeFromReader(ctx, file, params)
+	tempFile, err := os.CreateTemp("", "upload-*.tmp")
 	if err != nil {
-		logMessage(Error, fmt.Sprintf("Error transcribing file: %v", err))
-		return nil, err
+		log.Error("error creating temporary file", "error", err)
+		writeError(w, r, http.StatusInternalServerError, codeInternal, err.Error())
+		return
+	}
+	defer tempFile.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tempFile, hasher), file); err != nil {
+		log.Error("error copying file", "error", err)
+		os.Remove(tempFile.Name())
+		writeError(w, r, http.StatusInternalServerError, codeInternal, err.Error())
+		return
 	}
+	audioSHA256 := hex.EncodeToString(hasher.Sum(nil))
+
+	job := jobStore.create(usr.ID)
+	log.Info("created job", "job_id", job.ID, "audio_sha256", audioSHA256)
+	go runPipeline(job, tempFile.Name(), audioSHA256)
 
-	return &transcript, nil
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": job.ID})
 }
 
-func getAIResponse(prompt string) (string, error) {
-	url := "https://api.groq.com/openai/v1/chat/completions"
-	payload := map[string]interface{}{
-		"model": "llama-3.3-70b-versatile",
-		"messages": []map[string]string{
-			{
-				"role":    "user",
-				"content": prompt,
-			},
-		},
+// jobEventsHandler streams a job's phase transitions to the client as
+// Server-Sent Events until the job reaches "done" or "error".
+func jobEventsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := clerk.SessionClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	usr, err := user.Get(r.Context(), claims.Subject)
 	if err != nil {
-		return "", err
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(payloadBytes))
-	if err != nil {
-		return "", err
+	jobID := r.PathValue("id")
+	job, ok := jobStore.get(jobID, usr.ID)
+	if !ok {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "job not found")
+		return
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+groqApiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	logMessage(Info, fmt.Sprintf("request: %v", req))
-	if err != nil {
-		return "", err
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "streaming unsupported")
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, resp.Body)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	events, unsubscribe := job.subscribe()
+	defer unsubscribe()
+
+	// Catch a job that finished before we subscribed so the client always
+	// gets a terminal event instead of hanging.
+	if _, done := job.snapshot(); done {
+		writeSSEEvent(w, job.terminalEvent())
+		flusher.Flush()
+		return
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	for {
+		select {
+		case evt := <-events:
+			writeSSEEvent(w, evt)
+			flusher.Flush()
+			if evt.Phase == PhaseDone || evt.Phase == PhaseError {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
 	}
+}
 
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+// writeUpstreamError maps a provider error to a response: a tripped
+// circuit breaker fast-fails as 503 upstream_unavailable, anything else is
+// a plain 500.
+func writeUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	var breakerOpen *resilience.ErrBreakerOpen
+	if errors.As(err, &breakerOpen) {
+		writeError(w, r, http.StatusServiceUnavailable, codeUpstreamUnavailable, err.Error())
+		return
 	}
+	writeError(w, r, http.StatusInternalServerError, codeInternal, err.Error())
+}
 
-	err = json.Unmarshal(body, &result)
+// metricsHandler exposes circuit breaker state and retry counters in
+// Prometheus text exposition format.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	resilience.WriteMetrics(w)
+}
+
+// templatesHandler lists the registered prompt templates so a client can
+// populate a "template" picker for POST /summarize.
+func templatesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"templates": promptTemplates.List()})
+}
+
+func writeSSEEvent(w http.ResponseWriter, evt JobEvent) {
+	payload, err := json.Marshal(evt)
 	if err != nil {
-		return "", err
+		slog.Error("error encoding job event", "error", err)
+		return
 	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Phase, payload)
+}
 
-	logMessage(Info, fmt.Sprintf("response: %v", result))
+// newTranscriber picks a Transcriber implementation based on the
+// TRANSCRIBER env var ("assemblyai", "whisper_local", or "openai"),
+// defaulting to "assemblyai" for compatibility with existing deployments.
+func newTranscriber() providers.Transcriber {
+	switch os.Getenv("TRANSCRIBER") {
+	case "whisper_local":
+		binaryPath := os.Getenv("WHISPER_BINARY_PATH")
+		if binaryPath == "" {
+			binaryPath = "whisper-cli"
+		}
+		return providers.NewWhisperLocalTranscriber(binaryPath, os.Getenv("WHISPER_MODEL_PATH"))
+	case "openai":
+		return providers.NewOpenAIWhisperTranscriber(requireEnv("OPENAI_API_KEY"))
+	case "assemblyai", "":
+		return providers.NewAssemblyAITranscriber(requireEnv("ASSEMBLY_API_KEY"))
+	default:
+		slog.Error("unknown TRANSCRIBER", "value", os.Getenv("TRANSCRIBER"))
+		os.Exit(1)
+		return nil
+	}
+}
 
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no response from AI")
+// newSummarizer picks a Summarizer implementation based on the SUMMARIZER
+// env var ("groq", "openai", or "ollama"), defaulting to "groq" for
+// compatibility with existing deployments.
+func newSummarizer() providers.Summarizer {
+	switch os.Getenv("SUMMARIZER") {
+	case "openai":
+		return providers.NewOpenAISummarizer(requireEnv("OPENAI_API_KEY"))
+	case "ollama":
+		return providers.NewOllamaSummarizer(os.Getenv("OLLAMA_BASE_URL"), os.Getenv("OLLAMA_MODEL"))
+	case "groq", "":
+		return providers.NewGroqSummarizer(requireEnv("GROQ_API_KEY"))
+	default:
+		slog.Error("unknown SUMMARIZER", "value", os.Getenv("SUMMARIZER"))
+		os.Exit(1)
+		return nil
 	}
+}
 
-	return result.Choices[0].Message.Content, nil
+func requireEnv(name string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		slog.Error("required env var not set", "name", name)
+		os.Exit(1)
+	}
+	return value
 }
 
 func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Load .env file
-	err := godotenv.Load()
-	if err != nil {
-		logMessage(Error, "Error loading .env file")
+	if err := godotenv.Load(); err != nil {
+		slog.Error("error loading .env file", "error", err)
 		os.Exit(1)
 	}
 
-	assemblyApiKey = os.Getenv("ASSEMBLY_API_KEY")
-	if assemblyApiKey == "" {
-		logMessage(Error, "ASSEMBLY_API_KEY not set in .env file")
-		os.Exit(1)
-	}
+	transcriber = newTranscriber()
+	summarizer = newSummarizer()
 
-	groqApiKey = os.Getenv("GROQ_API_KEY")
-	if groqApiKey == "" {
-		logMessage(Error, "GROQ_API_KEY not set in .env file")
+	var err error
+	promptTemplates, err = templates.NewRegistry(os.Getenv("PROMPT_TEMPLATE_DIR"))
+	if err != nil {
+		slog.Error("error loading prompt templates", "error", err)
 		os.Exit(1)
 	}
+	defaultTemplate = os.Getenv("PROMPT_DEFAULT_TEMPLATE")
+	if defaultTemplate == "" {
+		defaultTemplate = "stem_lecture"
+	}
 
-	clerkApiKey := os.Getenv("CLERK_API_KEY")
-	if clerkApiKey == "" {
-		logMessage(Error, "CLERK_API_KEY not set in .env file")
+	dbPath := os.Getenv("JOBS_DB_PATH")
+	if dbPath == "" {
+		dbPath = "jobs.db"
+	}
+	sqliteStore, err := store.OpenSQLite(dbPath)
+	if err != nil {
+		slog.Error("error opening job store", "path", dbPath, "error", err)
 		os.Exit(1)
 	}
+	records = sqliteStore
+	defer sqliteStore.Close()
 
+	clerkApiKey := requireEnv("CLERK_API_KEY")
 	clerk.SetKey(clerkApiKey)
 
 	// Start the server
@@ -321,7 +413,15 @@ func main() {
 		clerkhttp.WithHeaderAuthorization()(protectedHandler),
 	)
 	mux.Handle("POST /summarize", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(handleSummaryRequest)))
-
-	logMessage(Info, "Starting server on :8080")
-	http.ListenAndServe("0.0.0.0:8080", mux)
+	mux.Handle("POST /jobs", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(createJobHandler)))
+	mux.Handle("GET /jobs/{id}/events", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(jobEventsHandler)))
+	mux.Handle("GET /jobs", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(listJobsHandler)))
+	mux.Handle("GET /jobs/{id}", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(getJobHandler)))
+	mux.Handle("POST /jobs/{id}/resummarize", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(resummarizeJobHandler)))
+	mux.Handle("DELETE /jobs/{id}", clerkhttp.WithHeaderAuthorization()(http.HandlerFunc(deleteJobHandler)))
+	mux.HandleFunc("GET /metrics", metricsHandler)
+	mux.HandleFunc("GET /templates", templatesHandler)
+
+	slog.Info("starting server", "addr", "0.0.0.0:8080")
+	http.ListenAndServe("0.0.0.0:8080", requestIDMiddleware(mux))
 }
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	aai "github.com/AssemblyAI/assemblyai-go-sdk"
+
+	"github.com/victorbratov/auto_note_server/resilience"
+)
+
+// AssemblyAITranscriber transcribes audio using the hosted AssemblyAI API.
+type AssemblyAITranscriber struct {
+	client  *aai.Client
+	breaker *resilience.Breaker
+}
+
+// NewAssemblyAITranscriber builds a Transcriber backed by AssemblyAI.
+func NewAssemblyAITranscriber(apiKey string) *AssemblyAITranscriber {
+	return &AssemblyAITranscriber{
+		client:  aai.NewClient(apiKey),
+		breaker: resilience.NewBreaker("assemblyai"),
+	}
+}
+
+// Describe identifies this provider for JobRecord.ProviderMetadata.
+func (t *AssemblyAITranscriber) Describe() string { return "assemblyai" }
+
+func (t *AssemblyAITranscriber) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (Transcript, error) {
+	params := &aai.TranscriptOptionalParams{
+		Punctuate:  aai.Bool(opts.Punctuate),
+		FormatText: aai.Bool(true),
+	}
+	if opts.Language != "" {
+		params.LanguageCode = aai.TranscriptLanguageCode(opts.Language)
+	}
+
+	seeker, seekable := audio.(io.Seeker)
+
+	var transcript aai.Transcript
+	err := resilience.Call(ctx, t.breaker, resilience.RetryConfig{MaxElapsedTime: time.Minute}, func() error {
+		if seekable {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		transcript, err = t.client.Transcripts.TranscribeFromReader(ctx, audio, params)
+		if err != nil {
+			return classifyAssemblyAIError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Transcript{}, err
+	}
+
+	text := ""
+	if transcript.Text != nil {
+		text = *transcript.Text
+	}
+	return Transcript{Text: text}, nil
+}
+
+// classifyAssemblyAIError wraps an AssemblyAI APIError in a
+// resilience.UpstreamError so the retry layer can see its status code and
+// any Retry-After header.
+func classifyAssemblyAIError(err error) error {
+	var apiErr aai.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	upstreamErr := &resilience.UpstreamError{Err: err, StatusCode: apiErr.Status}
+	if apiErr.Response != nil {
+		upstreamErr.RetryAfter = retryAfterFromHeader(apiErr.Response.Header.Get("Retry-After"))
+	}
+	return upstreamErr
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/victorbratov/auto_note_server/resilience"
+)
+
+const groqChatCompletionsURL = "https://api.groq.com/openai/v1/chat/completions"
+
+// GroqSummarizer summarizes text using Groq's OpenAI-compatible chat
+// completions endpoint.
+type GroqSummarizer struct {
+	apiKey  string
+	model   string
+	client  *http.Client
+	breaker *resilience.Breaker
+}
+
+// NewGroqSummarizer builds a Summarizer backed by Groq.
+func NewGroqSummarizer(apiKey string) *GroqSummarizer {
+	return &GroqSummarizer{
+		apiKey:  apiKey,
+		model:   "llama-3.3-70b-versatile",
+		client:  &http.Client{},
+		breaker: resilience.NewBreaker("groq"),
+	}
+}
+
+// Describe identifies this provider and model for JobRecord.ProviderMetadata.
+func (g *GroqSummarizer) Describe() string { return "groq:" + g.model }
+
+func (g *GroqSummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	var out strings.Builder
+	err := g.SummarizeStream(ctx, prompt, func(delta string) {
+		out.WriteString(delta)
+	})
+	return out.String(), err
+}
+
+func (g *GroqSummarizer) SummarizeStream(ctx context.Context, prompt string, onDelta func(delta string)) error {
+	payload := map[string]interface{}{
+		"model": g.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"stream": true,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var resp *http.Response
+	err = resilience.Call(ctx, g.breaker, resilience.RetryConfig{MaxElapsedTime: time.Minute}, func() error {
+		req, err := http.NewRequestWithContext(ctx, "POST", groqChatCompletionsURL, bytes.NewReader(payloadBytes))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+
+		resp, err = g.client.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return &resilience.UpstreamError{
+				Err:        fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, body),
+				StatusCode: resp.StatusCode,
+				RetryAfter: retryAfterFromHeader(resp.Header.Get("Retry-After")),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var chunk struct {
+		Choices []struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			break
+		}
+
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			onDelta(chunk.Choices[0].Delta.Content)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/victorbratov/auto_note_server/resilience"
+)
+
+// OllamaSummarizer summarizes text using a locally running Ollama server.
+type OllamaSummarizer struct {
+	baseURL string
+	model   string
+	client  *http.Client
+	breaker *resilience.Breaker
+}
+
+// NewOllamaSummarizer builds a Summarizer backed by Ollama. baseURL
+// defaults to "http://localhost:11434" when empty.
+func NewOllamaSummarizer(baseURL, model string) *OllamaSummarizer {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "llama3.2"
+	}
+	return &OllamaSummarizer{
+		baseURL: baseURL,
+		model:   model,
+		client:  &http.Client{},
+		breaker: resilience.NewBreaker("ollama"),
+	}
+}
+
+// Describe identifies this provider and model for JobRecord.ProviderMetadata.
+func (o *OllamaSummarizer) Describe() string { return "ollama:" + o.model }
+
+func (o *OllamaSummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	payload := map[string]interface{}{
+		"model":  o.model,
+		"prompt": prompt,
+		"stream": false,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Response string `json:"response"`
+	}
+	err = resilience.Call(ctx, o.breaker, resilience.RetryConfig{MaxElapsedTime: time.Minute}, func() error {
+		req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/api/generate", bytes.NewReader(payloadBytes))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &resilience.UpstreamError{
+				Err:        fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, body),
+				StatusCode: resp.StatusCode,
+				RetryAfter: retryAfterFromHeader(resp.Header.Get("Retry-After")),
+			}
+		}
+
+		return json.NewDecoder(resp.Body).Decode(&result)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.Response, nil
+}
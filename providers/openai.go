@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/victorbratov/auto_note_server/resilience"
+)
+
+// OpenAISummarizer summarizes text using OpenAI's chat completions
+// endpoint.
+type OpenAISummarizer struct {
+	apiKey  string
+	model   string
+	client  *http.Client
+	breaker *resilience.Breaker
+}
+
+// NewOpenAISummarizer builds a Summarizer backed by OpenAI chat
+// completions.
+func NewOpenAISummarizer(apiKey string) *OpenAISummarizer {
+	return &OpenAISummarizer{
+		apiKey:  apiKey,
+		model:   "gpt-4o-mini",
+		client:  &http.Client{},
+		breaker: resilience.NewBreaker("openai_chat"),
+	}
+}
+
+// Describe identifies this provider and model for JobRecord.ProviderMetadata.
+func (o *OpenAISummarizer) Describe() string { return "openai:" + o.model }
+
+func (o *OpenAISummarizer) Summarize(ctx context.Context, prompt string) (string, error) {
+	payload := map[string]interface{}{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	err = resilience.Call(ctx, o.breaker, resilience.RetryConfig{MaxElapsedTime: time.Minute}, func() error {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(payloadBytes))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &resilience.UpstreamError{
+				Err:        fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, body),
+				StatusCode: resp.StatusCode,
+				RetryAfter: retryAfterFromHeader(resp.Header.Get("Retry-After")),
+			}
+		}
+
+		return json.NewDecoder(resp.Body).Decode(&result)
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
+
+// OpenAIWhisperTranscriber transcribes audio using OpenAI's hosted Whisper
+// API.
+type OpenAIWhisperTranscriber struct {
+	apiKey  string
+	client  *http.Client
+	breaker *resilience.Breaker
+}
+
+// NewOpenAIWhisperTranscriber builds a Transcriber backed by the OpenAI
+// Whisper API.
+func NewOpenAIWhisperTranscriber(apiKey string) *OpenAIWhisperTranscriber {
+	return &OpenAIWhisperTranscriber{
+		apiKey:  apiKey,
+		client:  &http.Client{},
+		breaker: resilience.NewBreaker("openai_whisper"),
+	}
+}
+
+// Describe identifies this provider for JobRecord.ProviderMetadata.
+func (o *OpenAIWhisperTranscriber) Describe() string { return "openai:whisper-1" }
+
+func (o *OpenAIWhisperTranscriber) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (Transcript, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "audio.wav")
+	if err != nil {
+		return Transcript{}, err
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return Transcript{}, err
+	}
+	if err := writer.WriteField("model", "whisper-1"); err != nil {
+		return Transcript{}, err
+	}
+	if opts.Language != "" {
+		if err := writer.WriteField("language", opts.Language); err != nil {
+			return Transcript{}, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return Transcript{}, err
+	}
+
+	bodyBytes := body.Bytes()
+	var result struct {
+		Text string `json:"text"`
+	}
+	err = resilience.Call(ctx, o.breaker, resilience.RetryConfig{MaxElapsedTime: time.Minute}, func() error {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/audio/transcriptions", bytes.NewReader(bodyBytes))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			return &resilience.UpstreamError{
+				Err:        fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, respBody),
+				StatusCode: resp.StatusCode,
+				RetryAfter: retryAfterFromHeader(resp.Header.Get("Retry-After")),
+			}
+		}
+
+		return json.NewDecoder(resp.Body).Decode(&result)
+	})
+	if err != nil {
+		return Transcript{}, err
+	}
+
+	return Transcript{Text: result.Text}, nil
+}
@@ -0,0 +1,66 @@
+// Package providers defines the transcription and summarization interfaces
+// the server programs against, so the AssemblyAI/Groq pairing baked into
+// earlier versions of this server is just one of several interchangeable
+// backends selected at startup via env vars.
+package providers
+
+import (
+	"context"
+	"io"
+)
+
+// TranscribeOptions carries the knobs a Transcriber implementation may use.
+// Not every implementation honours every field.
+type TranscribeOptions struct {
+	// Language is a BCP-47 language hint, e.g. "en". Empty means auto-detect
+	// if the backend supports it.
+	Language string
+	// Punctuate asks the backend to restore punctuation and casing.
+	Punctuate bool
+}
+
+// Transcript is the result of transcribing an audio file.
+type Transcript struct {
+	Text string
+}
+
+// TranscriptEvent is one incremental fragment of a transcript as it is
+// produced, for backends that can stream partial results.
+type TranscriptEvent struct {
+	Text  string
+	Final bool
+}
+
+// Transcriber turns audio into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (Transcript, error)
+}
+
+// StreamingTranscriber is implemented by Transcribers that can report
+// partial results as they become available instead of only a final
+// transcript.
+type StreamingTranscriber interface {
+	Transcriber
+	TranscribeStream(ctx context.Context, audio io.Reader, opts TranscribeOptions) (<-chan TranscriptEvent, error)
+}
+
+// Summarizer turns a prompt (already containing the transcript) into a
+// summary.
+type Summarizer interface {
+	Summarize(ctx context.Context, prompt string) (string, error)
+}
+
+// StreamingSummarizer is implemented by Summarizers that can deliver their
+// response token-by-token via onDelta instead of only the finished text.
+type StreamingSummarizer interface {
+	Summarizer
+	SummarizeStream(ctx context.Context, prompt string, onDelta func(delta string)) error
+}
+
+// Describer is implemented by Transcribers and Summarizers that can report
+// their own provider name and model, e.g. "groq:llama-3.3-70b-versatile".
+// Callers persisting which backend produced a result (JobRecord.
+// ProviderMetadata) use this instead of hard-coding provider names.
+type Describer interface {
+	Describe() string
+}
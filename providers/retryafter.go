@@ -0,0 +1,22 @@
+package providers
+
+import (
+	"strconv"
+	"time"
+)
+
+// retryAfterFromHeader parses an HTTP Retry-After header value (either a
+// number of seconds or an HTTP-date) into a duration. It returns 0 if the
+// header is absent or unparsable, which callers treat as "no hint given".
+func retryAfterFromHeader(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := time.Parse(time.RFC1123, value); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WhisperLocalTranscriber shells out to a local whisper.cpp build (the
+// `main`/`whisper-cli` binary) so transcription can run fully offline. It
+// isn't wrapped in resilience.Call: that package's retry/circuit-breaker
+// logic is built around upstream HTTP status codes, and there's no
+// upstream here to fast-fail against, just a local subprocess.
+type WhisperLocalTranscriber struct {
+	// BinaryPath is the path to the whisper.cpp executable.
+	BinaryPath string
+	// ModelPath is the path to the ggml model file passed via -m.
+	ModelPath string
+}
+
+// NewWhisperLocalTranscriber builds a Transcriber backed by a local
+// whisper.cpp binary and model file.
+func NewWhisperLocalTranscriber(binaryPath, modelPath string) *WhisperLocalTranscriber {
+	return &WhisperLocalTranscriber{BinaryPath: binaryPath, ModelPath: modelPath}
+}
+
+// Describe identifies this provider and model for JobRecord.ProviderMetadata.
+func (w *WhisperLocalTranscriber) Describe() string {
+	return "whisper_local:" + filepath.Base(w.ModelPath)
+}
+
+func (w *WhisperLocalTranscriber) Transcribe(ctx context.Context, audio io.Reader, opts TranscribeOptions) (Transcript, error) {
+	tempFile, err := os.CreateTemp("", "whisper-input-*.wav")
+	if err != nil {
+		return Transcript{}, err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if _, err := io.Copy(tempFile, audio); err != nil {
+		return Transcript{}, err
+	}
+
+	outPrefix := strings.TrimSuffix(tempFile.Name(), filepath.Ext(tempFile.Name()))
+	args := []string{"-m", w.ModelPath, "-f", tempFile.Name(), "-otxt", "-of", outPrefix, "-nt"}
+	if opts.Language != "" {
+		args = append(args, "-l", opts.Language)
+	}
+
+	cmd := exec.CommandContext(ctx, w.BinaryPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Transcript{}, fmt.Errorf("whisper.cpp failed: %w: %s", err, stderr.String())
+	}
+
+	text, err := os.ReadFile(outPrefix + ".txt")
+	if err != nil {
+		return Transcript{}, fmt.Errorf("reading whisper.cpp output: %w", err)
+	}
+	defer os.Remove(outPrefix + ".txt")
+
+	return Transcript{Text: strings.TrimSpace(string(text))}, nil
+}
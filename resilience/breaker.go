@@ -0,0 +1,154 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is one of the three states of a Breaker.
+type BreakerState int
+
+const (
+	Closed BreakerState = iota
+	Open
+	HalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// outcome is one recorded call result, used to compute the failure rate
+// over a sliding window.
+type outcome struct {
+	at      time.Time
+	success bool
+}
+
+// Breaker is a closed/open/half-open circuit breaker with a sliding
+// failure-rate window: once at least MinRequests calls have landed inside
+// Window and the failure rate crosses FailureThreshold, the breaker opens
+// and fast-fails every call for OpenTimeout before allowing a single
+// half-open probe through.
+type Breaker struct {
+	Name             string
+	Window           time.Duration
+	MinRequests      int
+	FailureThreshold float64
+	OpenTimeout      time.Duration
+
+	mu               sync.Mutex
+	history          []outcome
+	state            BreakerState
+	openedAt         time.Time
+	halfOpenInFlight bool
+}
+
+// NewBreaker builds a Breaker with sensible defaults for an upstream API
+// call: a 1 minute sliding window, a minimum of 5 requests before it will
+// trip, a 50% failure threshold, and 30 seconds open before probing again.
+func NewBreaker(name string) *Breaker {
+	b := &Breaker{
+		Name:             name,
+		Window:           time.Minute,
+		MinRequests:      5,
+		FailureThreshold: 0.5,
+		OpenTimeout:      30 * time.Second,
+	}
+	register(b)
+	return b
+}
+
+// ErrBreakerOpen is returned by Allow when the breaker is open.
+type ErrBreakerOpen struct{ Name string }
+
+func (e *ErrBreakerOpen) Error() string { return "circuit breaker open for " + e.Name }
+
+// Allow reports whether a call should proceed. When it returns nil, the
+// caller must call the returned func with the call's outcome so the
+// breaker can update its state.
+func (b *Breaker) Allow() (func(success bool), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.openedAt) < b.OpenTimeout {
+			return nil, &ErrBreakerOpen{Name: b.Name}
+		}
+		// Open timeout elapsed: let exactly one probe through.
+		if b.halfOpenInFlight {
+			return nil, &ErrBreakerOpen{Name: b.Name}
+		}
+		b.state = HalfOpen
+		b.halfOpenInFlight = true
+	case HalfOpen:
+		if b.halfOpenInFlight {
+			return nil, &ErrBreakerOpen{Name: b.Name}
+		}
+		b.halfOpenInFlight = true
+	}
+
+	return func(success bool) { b.record(success) }, nil
+}
+
+func (b *Breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.history = append(b.history, outcome{at: now, success: success})
+	b.trim(now)
+
+	if b.state == HalfOpen {
+		b.halfOpenInFlight = false
+		if success {
+			b.state = Closed
+			b.history = nil
+		} else {
+			b.state = Open
+			b.openedAt = now
+		}
+		return
+	}
+
+	if len(b.history) < b.MinRequests {
+		return
+	}
+
+	failures := 0
+	for _, o := range b.history {
+		if !o.success {
+			failures++
+		}
+	}
+	if float64(failures)/float64(len(b.history)) >= b.FailureThreshold {
+		b.state = Open
+		b.openedAt = now
+	}
+}
+
+func (b *Breaker) trim(now time.Time) {
+	cutoff := now.Add(-b.Window)
+	i := 0
+	for ; i < len(b.history); i++ {
+		if b.history[i].at.After(cutoff) {
+			break
+		}
+	}
+	b.history = b.history[i:]
+}
+
+// State returns the breaker's current state.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestBreaker(name string) *Breaker {
+	return &Breaker{
+		Name:             name,
+		Window:           time.Minute,
+		MinRequests:      3,
+		FailureThreshold: 0.5,
+		OpenTimeout:      10 * time.Millisecond,
+	}
+}
+
+func TestBreakerStaysClosedBelowThreshold(t *testing.T) {
+	b := newTestBreaker("t-closed")
+
+	for i := 0; i < 3; i++ {
+		done, err := b.Allow()
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		done(true)
+	}
+	if got := b.State(); got != Closed {
+		t.Errorf("State() = %v, want Closed", got)
+	}
+}
+
+func TestBreakerOpensAtFailureThreshold(t *testing.T) {
+	b := newTestBreaker("t-opens")
+
+	outcomes := []bool{true, false, false}
+	for _, success := range outcomes {
+		done, err := b.Allow()
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		done(success)
+	}
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %v, want Open", got)
+	}
+
+	if _, err := b.Allow(); err == nil {
+		t.Error("Allow() on open breaker returned nil error, want *ErrBreakerOpen")
+	}
+}
+
+func TestBreakerHalfOpenProbeRecoversToClosed(t *testing.T) {
+	b := newTestBreaker("t-recovers")
+
+	for _, success := range []bool{false, false, false} {
+		done, _ := b.Allow()
+		done(success)
+	}
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %v, want Open", got)
+	}
+
+	time.Sleep(b.OpenTimeout + time.Millisecond)
+
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() after open timeout: %v", err)
+	}
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State() = %v, want HalfOpen", got)
+	}
+
+	done(true)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() = %v, want Closed", got)
+	}
+}
+
+func TestBreakerHalfOpenProbeFailureReopens(t *testing.T) {
+	b := newTestBreaker("t-reopens")
+
+	for _, success := range []bool{false, false, false} {
+		done, _ := b.Allow()
+		done(success)
+	}
+	time.Sleep(b.OpenTimeout + time.Millisecond)
+
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() after open timeout: %v", err)
+	}
+	done(false)
+
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %v, want Open", got)
+	}
+}
+
+func TestBreakerHalfOpenRejectsConcurrentProbes(t *testing.T) {
+	b := newTestBreaker("t-single-probe")
+
+	for _, success := range []bool{false, false, false} {
+		done, _ := b.Allow()
+		done(success)
+	}
+	time.Sleep(b.OpenTimeout + time.Millisecond)
+
+	if _, err := b.Allow(); err != nil {
+		t.Fatalf("first probe Allow(): %v", err)
+	}
+	if _, err := b.Allow(); err == nil {
+		t.Error("second concurrent probe Allow() returned nil error, want *ErrBreakerOpen")
+	}
+}
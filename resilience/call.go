@@ -0,0 +1,26 @@
+package resilience
+
+import "context"
+
+// Call runs op guarded by both a circuit breaker and retry-with-backoff:
+// if the breaker is open it fails fast with *ErrBreakerOpen instead of
+// queueing retries behind every request; otherwise it retries op per cfg
+// and reports the final outcome back to the breaker.
+func Call(ctx context.Context, b *Breaker, cfg RetryConfig, op func() error) error {
+	recordOutcome, err := b.Allow()
+	if err != nil {
+		return err
+	}
+
+	attempt := 0
+	err = Do(ctx, cfg, func() error {
+		attempt++
+		if attempt > 1 {
+			incRetries(b.Name)
+		}
+		return op()
+	})
+
+	recordOutcome(err == nil)
+	return err
+}
@@ -0,0 +1,63 @@
+package resilience
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+type registry struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+	retries  map[string]uint64
+}
+
+var globalRegistry = &registry{
+	breakers: make(map[string]*Breaker),
+	retries:  make(map[string]uint64),
+}
+
+func register(b *Breaker) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	globalRegistry.breakers[b.Name] = b
+}
+
+func incRetries(name string) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	globalRegistry.retries[name]++
+}
+
+// WriteMetrics renders every registered breaker's state and retry count in
+// Prometheus text exposition format, for the /metrics endpoint.
+func WriteMetrics(w io.Writer) {
+	globalRegistry.mu.Lock()
+	names := make([]string, 0, len(globalRegistry.breakers))
+	for name := range globalRegistry.breakers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	states := make(map[string]BreakerState, len(names))
+	for _, name := range names {
+		states[name] = globalRegistry.breakers[name].State()
+	}
+	retries := make(map[string]uint64, len(names))
+	for _, name := range names {
+		retries[name] = globalRegistry.retries[name]
+	}
+	globalRegistry.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP auto_note_breaker_state Circuit breaker state per upstream provider (0=closed, 1=open, 2=half_open).")
+	fmt.Fprintln(w, "# TYPE auto_note_breaker_state gauge")
+	for _, name := range names {
+		fmt.Fprintf(w, "auto_note_breaker_state{provider=%q} %d\n", name, states[name])
+	}
+
+	fmt.Fprintln(w, "# HELP auto_note_upstream_retries_total Retry attempts made against an upstream provider.")
+	fmt.Fprintln(w, "# TYPE auto_note_upstream_retries_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "auto_note_upstream_retries_total{provider=%q} %d\n", name, retries[name])
+	}
+}
@@ -0,0 +1,78 @@
+// Package resilience wraps calls to upstream providers (AssemblyAI, Groq,
+// ...) with retry-with-backoff and circuit-breaking so a flaky or
+// overloaded upstream doesn't take the whole server down with it.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// UpstreamError carries enough detail about an upstream HTTP failure for
+// Retry to decide whether it's worth retrying. Provider implementations
+// should return one of these instead of a bare error when they can
+// determine the response status.
+type UpstreamError struct {
+	Err        error
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *UpstreamError) Error() string { return e.Err.Error() }
+func (e *UpstreamError) Unwrap() error { return e.Err }
+
+func isRetryable(err error) (retry bool, retryAfter time.Duration) {
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		switch {
+		case upstreamErr.StatusCode == 429:
+			return true, upstreamErr.RetryAfter
+		case upstreamErr.StatusCode >= 500 && upstreamErr.StatusCode < 600:
+			return true, upstreamErr.RetryAfter
+		case upstreamErr.StatusCode != 0:
+			return false, 0
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr), 0
+}
+
+// RetryConfig controls Do's backoff schedule.
+type RetryConfig struct {
+	// MaxElapsedTime bounds the total time spent retrying. Zero means
+	// retry forever (until ctx is done).
+	MaxElapsedTime time.Duration
+}
+
+// Do runs op, retrying with exponential backoff and jitter when op returns
+// a retryable error (network errors, HTTP 429, HTTP 5xx), honouring any
+// Retry-After the upstream sent. Any other error is returned immediately.
+func Do(ctx context.Context, cfg RetryConfig, op func() error) error {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = cfg.MaxElapsedTime
+	bctx := backoff.WithContext(b, ctx)
+
+	return backoff.Retry(func() error {
+		err := op()
+		if err == nil {
+			return nil
+		}
+
+		retry, retryAfter := isRetryable(err)
+		if !retry {
+			return backoff.Permanent(err)
+		}
+		if retryAfter > 0 {
+			select {
+			case <-time.After(retryAfter):
+			case <-ctx.Done():
+			}
+		}
+		return err
+	}, bctx)
+}
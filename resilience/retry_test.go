@@ -0,0 +1,65 @@
+package resilience
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantRetry      bool
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:      "429 is retryable",
+			err:       &UpstreamError{Err: errors.New("rate limited"), StatusCode: 429, RetryAfter: 2 * time.Second},
+			wantRetry: true, wantRetryAfter: 2 * time.Second,
+		},
+		{
+			name:      "500 is retryable",
+			err:       &UpstreamError{Err: errors.New("server error"), StatusCode: 500},
+			wantRetry: true,
+		},
+		{
+			name:      "599 is retryable",
+			err:       &UpstreamError{Err: errors.New("server error"), StatusCode: 599},
+			wantRetry: true,
+		},
+		{
+			name:      "400 is not retryable",
+			err:       &UpstreamError{Err: errors.New("bad request"), StatusCode: 400},
+			wantRetry: false,
+		},
+		{
+			name:      "404 is not retryable",
+			err:       &UpstreamError{Err: errors.New("not found"), StatusCode: 404},
+			wantRetry: false,
+		},
+		{
+			name:      "network error is retryable",
+			err:       &net.DNSError{Err: "timeout", IsTimeout: true},
+			wantRetry: true,
+		},
+		{
+			name:      "plain error is not retryable",
+			err:       errors.New("boom"),
+			wantRetry: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, retryAfter := isRetryable(tt.err)
+			if retry != tt.wantRetry {
+				t.Errorf("isRetryable() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if retryAfter != tt.wantRetryAfter {
+				t.Errorf("isRetryable() retryAfter = %v, want %v", retryAfter, tt.wantRetryAfter)
+			}
+		})
+	}
+}
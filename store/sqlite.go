@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore is the default Store implementation, backed by a local
+// SQLite file via the pure-Go modernc.org/sqlite driver so the server
+// doesn't need cgo or a separate database to run.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (creating if necessary) a SQLite database at path and
+// runs its schema migration.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only supports one writer at a time; the job pipeline runs in
+	// background goroutines, so force a single connection rather than
+	// fighting SQLITE_BUSY errors under concurrent writes.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS jobs (
+			id                 TEXT PRIMARY KEY,
+			clerk_user_id      TEXT NOT NULL,
+			created_at         DATETIME NOT NULL,
+			status             TEXT NOT NULL,
+			audio_sha256       TEXT,
+			transcript         TEXT,
+			summary            TEXT,
+			prompt_template    TEXT,
+			provider_metadata  TEXT,
+			error              TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_jobs_user_audio ON jobs(clerk_user_id, audio_sha256);
+		CREATE INDEX IF NOT EXISTS idx_jobs_user_created ON jobs(clerk_user_id, created_at);
+	`)
+	return err
+}
+
+func (s *SQLiteStore) Create(ctx context.Context, job JobRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, clerk_user_id, created_at, status, audio_sha256, transcript, summary, prompt_template, provider_metadata, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.ClerkUserID, job.CreatedAt, job.Status, job.AudioSHA256,
+		job.Transcript, job.Summary, job.PromptTemplate, job.ProviderMetadata, job.Error)
+	return err
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, id, userID string) (JobRecord, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, clerk_user_id, created_at, status, audio_sha256, transcript, summary, prompt_template, provider_metadata, error
+		FROM jobs WHERE id = ? AND clerk_user_id = ?`, id, userID)
+	return scanJob(row)
+}
+
+func (s *SQLiteStore) FindByAudioHash(ctx context.Context, userID, audioSHA256 string) (JobRecord, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, clerk_user_id, created_at, status, audio_sha256, transcript, summary, prompt_template, provider_metadata, error
+		FROM jobs WHERE clerk_user_id = ? AND audio_sha256 = ? AND status = ?
+		ORDER BY created_at DESC LIMIT 1`, userID, audioSHA256, StatusDone)
+	return scanJob(row)
+}
+
+func (s *SQLiteStore) List(ctx context.Context, userID string, limit, offset int) ([]JobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, clerk_user_id, created_at, status, audio_sha256, transcript, summary, prompt_template, provider_metadata, error
+		FROM jobs WHERE clerk_user_id = ?
+		ORDER BY created_at DESC LIMIT ? OFFSET ?`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []JobRecord
+	for rows.Next() {
+		job, err := scanJobRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (s *SQLiteStore) Update(ctx context.Context, job JobRecord) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, transcript = ?, summary = ?, prompt_template = ?, provider_metadata = ?, error = ?
+		WHERE id = ? AND clerk_user_id = ?`,
+		job.Status, job.Transcript, job.Summary, job.PromptTemplate, job.ProviderMetadata, job.Error,
+		job.ID, job.ClerkUserID)
+	if err != nil {
+		return err
+	}
+	return requireRowAffected(result)
+}
+
+func (s *SQLiteStore) Delete(ctx context.Context, id, userID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = ? AND clerk_user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	return requireRowAffected(result)
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func requireRowAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (JobRecord, error) {
+	return scanJobRow(row)
+}
+
+func scanJobRow(row rowScanner) (JobRecord, error) {
+	var job JobRecord
+	var createdAt time.Time
+	err := row.Scan(&job.ID, &job.ClerkUserID, &createdAt, &job.Status, &job.AudioSHA256,
+		&job.Transcript, &job.Summary, &job.PromptTemplate, &job.ProviderMetadata, &job.Error)
+	if err == sql.ErrNoRows {
+		return JobRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return JobRecord{}, err
+	}
+	job.CreatedAt = createdAt
+	return job, nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s, err := OpenSQLite(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteStoreCreateGetRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	rec := JobRecord{
+		ID:               "job-1",
+		ClerkUserID:      "user-a",
+		CreatedAt:        time.Now().UTC().Truncate(time.Second),
+		Status:           StatusDone,
+		AudioSHA256:      "deadbeef",
+		Transcript:       "hello world",
+		Summary:          "a summary",
+		PromptTemplate:   "meeting_minutes",
+		ProviderMetadata: "transcriber=assemblyai summarizer=groq:llama-3.3-70b-versatile",
+	}
+	if err := s.Create(ctx, rec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := s.Get(ctx, "job-1", "user-a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ProviderMetadata != rec.ProviderMetadata {
+		t.Errorf("ProviderMetadata = %q, want %q", got.ProviderMetadata, rec.ProviderMetadata)
+	}
+	if got.Summary != rec.Summary || got.Transcript != rec.Transcript {
+		t.Errorf("Get returned %+v, want summary/transcript matching %+v", got, rec)
+	}
+}
+
+func TestSQLiteStoreScopesByUser(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.Create(ctx, JobRecord{ID: "job-1", ClerkUserID: "user-a", CreatedAt: time.Now(), Status: StatusDone}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := s.Get(ctx, "job-1", "user-b"); err != ErrNotFound {
+		t.Errorf("Get with wrong user = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Update(ctx, JobRecord{ID: "job-1", ClerkUserID: "user-b", Status: StatusError}); err != ErrNotFound {
+		t.Errorf("Update with wrong user = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Delete(ctx, "job-1", "user-b"); err != ErrNotFound {
+		t.Errorf("Delete with wrong user = %v, want ErrNotFound", err)
+	}
+
+	list, err := s.List(ctx, "user-b", 10, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("List for user-b returned %d records, want 0", len(list))
+	}
+
+	list, err = s.List(ctx, "user-a", 10, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("List for user-a returned %d records, want 1", len(list))
+	}
+}
+
+func TestSQLiteStoreFindByAudioHashOnlyMatchesDone(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.Create(ctx, JobRecord{
+		ID: "job-1", ClerkUserID: "user-a", CreatedAt: time.Now(),
+		Status: StatusTranscribing, AudioSHA256: "hash1",
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := s.FindByAudioHash(ctx, "user-a", "hash1"); err != ErrNotFound {
+		t.Errorf("FindByAudioHash for in-progress job = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Update(ctx, JobRecord{
+		ID: "job-1", ClerkUserID: "user-a", Status: StatusDone,
+		Transcript: "t", Summary: "s",
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	found, err := s.FindByAudioHash(ctx, "user-a", "hash1")
+	if err != nil {
+		t.Fatalf("FindByAudioHash: %v", err)
+	}
+	if found.ID != "job-1" {
+		t.Errorf("FindByAudioHash returned job %q, want job-1", found.ID)
+	}
+}
@@ -0,0 +1,56 @@
+// Package store persists job records so a transcript/summary survives
+// after the HTTP response that produced it, and can be listed or
+// re-summarized later. Store is an interface so the default SQLite-backed
+// implementation (zero-cgo, via modernc.org/sqlite) can be swapped for a
+// Postgres-backed one without touching callers.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// JobStatus mirrors the phase of a job's transcribe-then-summarize
+// pipeline, persisted so a client can query it after disconnecting.
+type JobStatus string
+
+const (
+	StatusPending      JobStatus = "pending"
+	StatusTranscribing JobStatus = "transcribing"
+	StatusSummarizing  JobStatus = "summarizing"
+	StatusDone         JobStatus = "done"
+	StatusError        JobStatus = "error"
+)
+
+// JobRecord is a persisted pipeline run.
+type JobRecord struct {
+	ID               string    `json:"id"`
+	ClerkUserID      string    `json:"clerk_user_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	Status           JobStatus `json:"status"`
+	AudioSHA256      string    `json:"audio_sha256,omitempty"`
+	Transcript       string    `json:"transcript,omitempty"`
+	Summary          string    `json:"summary,omitempty"`
+	PromptTemplate   string    `json:"prompt_template,omitempty"`
+	ProviderMetadata string    `json:"provider_metadata,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// ErrNotFound is returned by Get, FindByAudioHash, Update, and Delete when
+// no matching record exists for the given (id, userID) pair. A record
+// belonging to a different user is indistinguishable from a missing one.
+var ErrNotFound = errors.New("job record not found")
+
+// Store persists JobRecords, scoped per Clerk user: every lookup takes the
+// requesting user's ID and must behave as if records belonging to other
+// users don't exist.
+type Store interface {
+	Create(ctx context.Context, job JobRecord) error
+	Get(ctx context.Context, id, userID string) (JobRecord, error)
+	List(ctx context.Context, userID string, limit, offset int) ([]JobRecord, error)
+	FindByAudioHash(ctx context.Context, userID, audioSHA256 string) (JobRecord, error)
+	Update(ctx context.Context, job JobRecord) error
+	Delete(ctx context.Context, id, userID string) error
+	Close() error
+}
@@ -0,0 +1,191 @@
+// Package templates implements the prompt template registry used to turn a
+// raw transcript into a summarizer prompt. Templates are Go text/template
+// strings with a small YAML-ish front matter declaring a description and the
+// extra variables (beyond the transcript itself) the template accepts. A
+// handful of built-in templates ship embedded in the binary; pointing
+// PROMPT_TEMPLATE_DIR at a directory of .tmpl files adds to or overrides
+// them without a rebuild.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed builtin/*.tmpl
+var builtinFS embed.FS
+
+// Template is a named prompt template: Body is rendered with the transcript
+// under the "text" key plus whatever the caller supplies in Vars.
+type Template struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Vars        []string `json:"vars,omitempty"`
+	Body        string   `json:"-"`
+}
+
+// Registry holds the set of templates available to summarize against,
+// keyed by name.
+type Registry struct {
+	templates map[string]Template
+}
+
+// NewRegistry loads the built-in templates and, if dir is non-empty, overlays
+// every "*.tmpl" file found there on top of them (a file with the same
+// base name as a built-in replaces it).
+func NewRegistry(dir string) (*Registry, error) {
+	reg := &Registry{templates: make(map[string]Template)}
+
+	if err := reg.loadFS(builtinFS, "builtin"); err != nil {
+		return nil, fmt.Errorf("loading built-in templates: %w", err)
+	}
+
+	if dir == "" {
+		return reg, nil
+	}
+	if err := reg.loadFS(os.DirFS(dir), "."); err != nil {
+		return nil, fmt.Errorf("loading templates from %s: %w", dir, err)
+	}
+	return reg, nil
+}
+
+func (r *Registry) loadFS(fsys fs.FS, root string) error {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+			continue
+		}
+		raw, err := fs.ReadFile(fsys, filepath.Join(root, entry.Name()))
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		tmpl, err := parseTemplate(name, string(raw))
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		r.templates[name] = tmpl
+	}
+	return nil
+}
+
+// parseTemplate splits a template file into its front matter and body. The
+// front matter is delimited by "---" lines and declares "description:" and
+// "vars:" (a comma-separated list, which may be empty).
+func parseTemplate(name, raw string) (Template, error) {
+	lines := strings.Split(raw, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "---" {
+		return Template{}, fmt.Errorf("missing front matter")
+	}
+
+	tmpl := Template{Name: name}
+	i := 1
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			i++
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "description":
+			tmpl.Description = value
+		case "vars":
+			if value != "" {
+				for _, v := range strings.Split(value, ",") {
+					tmpl.Vars = append(tmpl.Vars, strings.TrimSpace(v))
+				}
+			}
+		}
+	}
+	tmpl.Body = strings.Join(lines[i:], "\n")
+	return tmpl, nil
+}
+
+// List returns every template, sorted by name, for GET /templates.
+func (r *Registry) List() []Template {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]Template, 0, len(names))
+	for _, name := range names {
+		out = append(out, r.templates[name])
+	}
+	return out
+}
+
+// ErrUnknownVar is returned by Render when vars contains a key the named
+// template didn't declare.
+type ErrUnknownVar struct {
+	Template string
+	Var      string
+}
+
+func (e *ErrUnknownVar) Error() string {
+	return fmt.Sprintf("template %q does not declare variable %q", e.Template, e.Var)
+}
+
+// ErrUnknownTemplate is returned by Render when name isn't registered.
+type ErrUnknownTemplate struct {
+	Name string
+}
+
+func (e *ErrUnknownTemplate) Error() string {
+	return fmt.Sprintf("unknown template %q", e.Name)
+}
+
+// Render fills the named template with text under the "text" key and vars
+// under their own keys, rejecting any var the template didn't declare.
+// Rendering uses missingkey=error, so a typo'd placeholder in the template
+// body itself fails loudly instead of producing a silently incomplete
+// prompt.
+func (r *Registry) Render(name, text string, vars map[string]string) (string, error) {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return "", &ErrUnknownTemplate{Name: name}
+	}
+
+	declared := make(map[string]struct{}, len(tmpl.Vars))
+	for _, v := range tmpl.Vars {
+		declared[v] = struct{}{}
+	}
+
+	data := map[string]string{"text": text}
+	for v := range declared {
+		data[v] = ""
+	}
+	for k, v := range vars {
+		if _, ok := declared[k]; !ok {
+			return "", &ErrUnknownVar{Template: name, Var: k}
+		}
+		data[k] = v
+	}
+
+	parsed, err := template.New(name).Option("missingkey=error").Parse(tmpl.Body)
+	if err != nil {
+		return "", fmt.Errorf("parsing template %q: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderOptionalVarsDefaultEmpty(t *testing.T) {
+	reg, err := NewRegistry("")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]string
+	}{
+		{name: "flashcards without vars", template: "flashcards", vars: nil},
+		{name: "exam_prep without vars", template: "exam_prep", vars: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := reg.Render(tt.template, "some transcript", tt.vars); err != nil {
+				t.Fatalf("Render(%q) with no vars: %v", tt.template, err)
+			}
+		})
+	}
+}
+
+func TestRenderSuppliedVarOverridesDefault(t *testing.T) {
+	reg, err := NewRegistry("")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	out, err := reg.Render("flashcards", "transcript text", map[string]string{"count": "5"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "Generate 5 flashcards"; !strings.Contains(out, want) {
+		t.Errorf("Render output missing %q, got: %s", want, out)
+	}
+}
+
+func TestRenderUnknownVarRejected(t *testing.T) {
+	reg, err := NewRegistry("")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	_, err = reg.Render("flashcards", "text", map[string]string{"bogus": "x"})
+	if _, ok := err.(*ErrUnknownVar); !ok {
+		t.Fatalf("expected *ErrUnknownVar, got %v", err)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	reg, err := NewRegistry("")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	_, err = reg.Render("does-not-exist", "text", nil)
+	if _, ok := err.(*ErrUnknownTemplate); !ok {
+		t.Fatalf("expected *ErrUnknownTemplate, got %v", err)
+	}
+}